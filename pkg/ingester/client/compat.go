@@ -0,0 +1,277 @@
+package client
+
+import (
+	"unsafe"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// LabelAdapter is a labels.Label that can be built without pulling in the
+// labels package, used so that sorting and field names exactly match
+// labels.Label's memory layout (see FromLabelAdaptersToLabels).
+type LabelAdapter labels.Label
+
+// ToQueryRequest builds a QueryRequest proto.
+func ToQueryRequest(from, to model.Time, matchers []*labels.Matcher) (*QueryRequest, error) {
+	ms, err := toLabelMatchers(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueryRequest{
+		StartTimestampMs: int64(from),
+		EndTimestampMs:   int64(to),
+		Matchers:         ms,
+	}, nil
+}
+
+// FromQueryRequest unpacks a QueryRequest proto.
+func FromQueryRequest(req *QueryRequest) (model.Time, model.Time, []*labels.Matcher, error) {
+	matchers, err := fromLabelMatchers(req.Matchers)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	from := model.Time(req.StartTimestampMs)
+	to := model.Time(req.EndTimestampMs)
+	return from, to, matchers, nil
+}
+
+// ToLabelNamesRequest builds a LabelNamesRequest proto, allowing the
+// distributor to push label-name queries with matchers all the way down to
+// ingesters and store-gateways instead of fetching all series and filtering
+// client-side.
+func ToLabelNamesRequest(from, to model.Time, matchers []*labels.Matcher) (*LabelNamesRequest, error) {
+	ms, err := toLabelMatchers(matchers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LabelNamesRequest{
+		StartTimestampMs: int64(from),
+		EndTimestampMs:   int64(to),
+		Matchers:         ms,
+	}, nil
+}
+
+// FromLabelNamesRequest unpacks a LabelNamesRequest proto.
+func FromLabelNamesRequest(req *LabelNamesRequest) (model.Time, model.Time, []*labels.Matcher, error) {
+	matchers, err := fromLabelMatchers(req.Matchers)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	from := model.Time(req.StartTimestampMs)
+	to := model.Time(req.EndTimestampMs)
+	return from, to, matchers, nil
+}
+
+// ToQueryResponse builds a QueryResponse proto.
+func ToQueryResponse(matrix model.Matrix) *QueryResponse {
+	resp := &QueryResponse{}
+	for _, ss := range matrix {
+		ts := TimeSeries{
+			Labels:  fromMetricsToLabelPairs(ss.Metric),
+			Samples: make([]Sample, 0, len(ss.Values)),
+		}
+		for _, s := range ss.Values {
+			ts.Samples = append(ts.Samples, Sample{
+				Value:       float64(s.Value),
+				TimestampMs: int64(s.Timestamp),
+			})
+		}
+		resp.Timeseries = append(resp.Timeseries, ts)
+	}
+	return resp
+}
+
+// FromQueryResponse unpacks a QueryResponse proto.
+func FromQueryResponse(resp *QueryResponse) model.Matrix {
+	m := make(model.Matrix, 0, len(resp.Timeseries))
+	for _, ts := range resp.Timeseries {
+		ss := &model.SampleStream{
+			Metric: fromLabelPairsToMetric(ts.Labels),
+			Values: make([]model.SamplePair, 0, len(ts.Samples)),
+		}
+		for _, s := range ts.Samples {
+			ss.Values = append(ss.Values, model.SamplePair{
+				Value:     model.SampleValue(s.Value),
+				Timestamp: model.Time(s.TimestampMs),
+			})
+		}
+		m = append(m, ss)
+	}
+	return m
+}
+
+func toLabelMatchers(matchers []*labels.Matcher) ([]*LabelMatcher, error) {
+	result := make([]*LabelMatcher, 0, len(matchers))
+	for _, matcher := range matchers {
+		var mType MatchType
+		switch matcher.Type {
+		case labels.MatchEqual:
+			mType = EQUAL
+		case labels.MatchNotEqual:
+			mType = NOT_EQUAL
+		case labels.MatchRegexp:
+			mType = REGEX_MATCH
+		case labels.MatchNotRegexp:
+			mType = REGEX_NO_MATCH
+		default:
+			return nil, errInvalidMatcherType(matcher.Type)
+		}
+		result = append(result, &LabelMatcher{
+			Type:  mType,
+			Name:  matcher.Name,
+			Value: matcher.Value,
+		})
+	}
+	return result, nil
+}
+
+func fromLabelMatchers(matchers []*LabelMatcher) ([]*labels.Matcher, error) {
+	result := make([]*labels.Matcher, 0, len(matchers))
+	for _, matcher := range matchers {
+		var mType labels.MatchType
+		switch matcher.Type {
+		case EQUAL:
+			mType = labels.MatchEqual
+		case NOT_EQUAL:
+			mType = labels.MatchNotEqual
+		case REGEX_MATCH:
+			mType = labels.MatchRegexp
+		case REGEX_NO_MATCH:
+			mType = labels.MatchNotRegexp
+		default:
+			return nil, errInvalidMatcherType(matcher.Type)
+		}
+		matcher, err := labels.NewMatcher(mType, matcher.Name, matcher.Value)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, matcher)
+	}
+	return result, nil
+}
+
+func fromMetricsToLabelPairs(metric model.Metric) []LabelPair {
+	result := make([]LabelPair, 0, len(metric))
+	for k, v := range metric {
+		result = append(result, LabelPair{
+			Name:  []byte(k),
+			Value: []byte(v),
+		})
+	}
+	return result
+}
+
+func fromLabelPairsToMetric(pairs []LabelPair) model.Metric {
+	metric := make(model.Metric, len(pairs))
+	for _, pair := range pairs {
+		metric[model.LabelName(pair.Name)] = model.LabelValue(pair.Value)
+	}
+	return metric
+}
+
+// FromLabelAdaptersToLabels casts []LabelAdapter to labels.Labels.
+// It uses unsafe, but as LabelAdapter == labels.Label, this is safe, and
+// is the best way to avoid copying the strings.
+func FromLabelAdaptersToLabels(ls []LabelAdapter) labels.Labels {
+	return *(*labels.Labels)(unsafe.Pointer(&ls))
+}
+
+// FromLabelAdaptersToLabelsWithCopy converts []LabelAdapter to labels.Labels,
+// copying the strings so the result shares no memory with the input.
+func FromLabelAdaptersToLabelsWithCopy(ls []LabelAdapter) labels.Labels {
+	result := make(labels.Labels, 0, len(ls))
+	for _, l := range ls {
+		result = append(result, labels.Label{
+			Name:  string([]byte(l.Name)),
+			Value: string([]byte(l.Value)),
+		})
+	}
+	return result
+}
+
+// FromLabelsToLabelAdapters casts labels.Labels to []LabelAdapter.
+func FromLabelsToLabelAdapters(ls labels.Labels) []LabelAdapter {
+	return *(*[]LabelAdapter)(unsafe.Pointer(&ls))
+}
+
+// MetricMetadataMetricTypeToMetricType maps a Cortex MetricMetadata_MetricType
+// to a Prometheus textparse.MetricType.
+func MetricMetadataMetricTypeToMetricType(mType MetricMetadata_MetricType) textparse.MetricType {
+	switch mType {
+	case COUNTER:
+		return textparse.MetricTypeCounter
+	case GAUGE:
+		return textparse.MetricTypeGauge
+	case HISTOGRAM:
+		return textparse.MetricTypeHistogram
+	case GAUGEHISTOGRAM:
+		return textparse.MetricTypeGaugeHistogram
+	case SUMMARY:
+		return textparse.MetricTypeSummary
+	case INFO:
+		return textparse.MetricTypeInfo
+	case STATESET:
+		return textparse.MetricTypeStateset
+	default:
+		return textparse.MetricTypeUnknown
+	}
+}
+
+// sepByte is used to separate label names, label values and other strings
+// when calculating their combined hash value (aka signature aka fingerprint).
+const sepByte = '\xff'
+
+const (
+	offset64 = 14695981039346656037
+	prime64  = 1099511628211
+)
+
+// Fingerprint calculates a fast (but, under FNV1a, possibly colliding)
+// fingerprint of the given labels, using the process-wide HashingAlgorithm
+// (see SetHashingAlgorithm). See TestFingerprintCollisions for pathological
+// cases that require client.FPMapper to disambiguate.
+func Fingerprint(labels labels.Labels) model.Fingerprint {
+	switch hashingAlgorithm {
+	case XXHash:
+		return fingerprintXXHash(labels)
+	default:
+		return fingerprintFNV1a(labels)
+	}
+}
+
+func fingerprintFNV1a(labels labels.Labels) model.Fingerprint {
+	sum := uint64(offset64)
+	for _, label := range labels {
+		sum = hashAdd(sum, label.Name)
+		sum = hashAddByte(sum, sepByte)
+		sum = hashAdd(sum, label.Value)
+		sum = hashAddByte(sum, sepByte)
+	}
+	return model.Fingerprint(sum)
+}
+
+// hashAdd adds a string to a fnv64a hash value, returning the updated hash.
+func hashAdd(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= prime64
+	}
+	return h
+}
+
+// hashAddByte adds a byte to a fnv64a hash value, returning the updated hash.
+func hashAddByte(h uint64, b byte) uint64 {
+	h ^= uint64(b)
+	h *= prime64
+	return h
+}
+
+type errInvalidMatcherType labels.MatchType
+
+func (e errInvalidMatcherType) Error() string {
+	return "invalid matcher type"
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"sort"
@@ -62,6 +63,87 @@ func TestQueryRequest(t *testing.T) {
 	}
 }
 
+func TestLabelNamesRequest(t *testing.T) {
+	from, to := model.Time(int64(0)), model.Time(int64(10))
+	matchers := []*labels.Matcher{}
+	matcher1, err := labels.NewMatcher(labels.MatchEqual, "foo", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matchers = append(matchers, matcher1)
+
+	matcher2, err := labels.NewMatcher(labels.MatchNotEqual, "bar", "2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matchers = append(matchers, matcher2)
+
+	req, err := ToLabelNamesRequest(from, to, matchers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	haveFrom, haveTo, haveMatchers, err := FromLabelNamesRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(haveFrom, from) {
+		t.Fatalf("Bad from FromLabelNamesRequest(ToLabelNamesRequest) round trip")
+	}
+	if !reflect.DeepEqual(haveTo, to) {
+		t.Fatalf("Bad to FromLabelNamesRequest(ToLabelNamesRequest) round trip")
+	}
+	if !reflect.DeepEqual(haveMatchers, matchers) {
+		t.Fatalf("Bad have FromLabelNamesRequest(ToLabelNamesRequest) round trip - %v != %v", haveMatchers, matchers)
+	}
+}
+
+// TestQueryRequestRepeatedMatcherName checks that two matchers on the same
+// label name, a legitimate and common query shape (e.g.
+// {instance=~"web.*", instance!="web99"}), round-trip through
+// ToQueryRequest/FromQueryRequest rather than being rejected: the
+// duplicate-label-name check is about a stored series' label set, not a
+// query's matcher set.
+func TestQueryRequestRepeatedMatcherName(t *testing.T) {
+	from, to := model.Time(int64(0)), model.Time(int64(10))
+
+	matcher1, err := labels.NewMatcher(labels.MatchRegexp, "instance", "web.*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matcher2, err := labels.NewMatcher(labels.MatchNotEqual, "instance", "web99")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matchers := []*labels.Matcher{matcher1, matcher2}
+
+	req, err := ToQueryRequest(from, to, matchers)
+	if err != nil {
+		t.Fatalf("expected matchers repeating a label name to be accepted, got %v", err)
+	}
+
+	_, _, haveMatchers, err := FromQueryRequest(req)
+	if err != nil {
+		t.Fatalf("expected matchers repeating a label name to be accepted, got %v", err)
+	}
+	if !reflect.DeepEqual(haveMatchers, matchers) {
+		t.Fatalf("Bad have FromQueryRequest(ToQueryRequest) round trip - %v != %v", haveMatchers, matchers)
+	}
+}
+
+func TestValidateLabelsDuplicateLabelName(t *testing.T) {
+	input := []LabelAdapter{{Name: "__name__", Value: "up"}, {Name: "foo", Value: "1"}, {Name: "foo", Value: "2"}}
+
+	var dupErr *ErrDuplicateLabelName
+	if err := ValidateLabels(FromLabelAdaptersToLabels(input)); !errors.As(err, &dupErr) {
+		t.Fatalf("expected ValidateLabels(FromLabelAdaptersToLabels(...)) to return ErrDuplicateLabelName, got %v", err)
+	}
+	if err := ValidateLabels(FromLabelAdaptersToLabelsWithCopy(input)); !errors.As(err, &dupErr) {
+		t.Fatalf("expected ValidateLabels(FromLabelAdaptersToLabelsWithCopy(...)) to return ErrDuplicateLabelName, got %v", err)
+	}
+}
+
 func buildTestMatrix(numSeries int, samplesPerSeries int, offset int) model.Matrix {
 	m := make(model.Matrix, 0, numSeries)
 	for i := 0; i < numSeries; i++ {
@@ -206,3 +288,40 @@ func verifyCollision(t *testing.T, collision bool, ls1 labels.Labels, ls2 labels
 		t.Errorf("expected different fingerprints for %v (%016x) and %v (%016x)", ls1.String(), Fingerprint(ls1), ls2.String(), Fingerprint(ls2))
 	}
 }
+
+// TestFingerprintCollisionsXXHash checks that the pathological pairs that
+// collide under FNV1a do not collide once the package is switched to
+// XXHash.
+func TestFingerprintCollisionsXXHash(t *testing.T) {
+	xx := NewFingerprinter(XXHash)
+	verifyNoCollisionXXHash := func(ls1, ls2 labels.Labels) {
+		if xx.Fingerprint(ls1) == xx.Fingerprint(ls2) {
+			t.Errorf("expected XXHash to not collide for %v (%016x) and %v (%016x)", ls1.String(), xx.Fingerprint(ls1), ls2.String(), xx.Fingerprint(ls2))
+		}
+	}
+
+	c1 := labels.FromStrings("8yn0iYCKYHlIj4-BwPqk", "hello")
+	c2 := labels.FromStrings("GReLUrM4wMqfg9yzV3KQ", "hello")
+	verifyNoCollisionXXHash(c1, c2)
+
+	const _label1 = "ypfajYg2lsv"
+	const _label2 = "KiqbryhzUpn"
+
+	metric := labels.NewBuilder(labels.FromStrings("__name__", "logs"))
+	c1 = metric.Set("_", _label1).Labels()
+	c2 = metric.Set("_", _label2).Labels()
+	verifyNoCollisionXXHash(c1, c2)
+
+	metric = labels.NewBuilder(labels.FromStrings("__name__", "up", "instance", "hello"))
+	c1 = metric.Set("_", _label1).Labels()
+	c2 = metric.Set("_", _label2).Labels()
+	verifyNoCollisionXXHash(c1, c2)
+
+	const Alabel1 = "K6sjsNNczPl"
+	const Alabel2 = "cswpLMIZpwt"
+
+	metric = labels.NewBuilder(labels.FromStrings("__name__", "up", "Z", "hello"))
+	c1 = metric.Set("A", Alabel1).Labels()
+	c2 = metric.Set("A", Alabel2).Labels()
+	verifyNoCollisionXXHash(c1, c2)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"github.com/cespare/xxhash/v2"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// HashingAlgorithm selects the hash function used to calculate fast
+// fingerprints for label sets.
+type HashingAlgorithm int
+
+const (
+	// FNV1a is the original hashing algorithm used by client.Fingerprint. It
+	// is prone to the pathological collisions demonstrated by
+	// TestFingerprintCollisions, which client.FPMapper exists to resolve.
+	FNV1a HashingAlgorithm = iota
+	// XXHash uses github.com/cespare/xxhash/v2, which the wider Prometheus
+	// ecosystem has adopted because it does not exhibit FNV1a's pathological
+	// collisions.
+	XXHash
+)
+
+// hashingAlgorithm is the algorithm used by the package-level Fingerprint
+// function. Ingesters persist fingerprints in their chunk indexes and WALs,
+// so this must not change underneath a running cluster: roll out readers
+// that understand XXHash fingerprints first, then call
+// SetHashingAlgorithm(XXHash) on writers once every reader has been
+// upgraded.
+var hashingAlgorithm = FNV1a
+
+// SetHashingAlgorithm changes the algorithm used by the package-level
+// Fingerprint function for the remainder of the process lifetime. It is not
+// safe to call concurrently with calls to Fingerprint, and is intended to be
+// called once at startup based on configuration.
+func SetHashingAlgorithm(algo HashingAlgorithm) {
+	hashingAlgorithm = algo
+}
+
+// Fingerprinter calculates fingerprints using a fixed HashingAlgorithm,
+// independent of the package-level default. Use it where both the old and
+// new algorithm are needed side by side during a migration, e.g. to compare
+// against fingerprints computed by a peer that hasn't upgraded yet.
+type Fingerprinter struct {
+	algo HashingAlgorithm
+}
+
+// NewFingerprinter returns a Fingerprinter using the given algorithm.
+func NewFingerprinter(algo HashingAlgorithm) Fingerprinter {
+	return Fingerprinter{algo: algo}
+}
+
+// Fingerprint calculates the fingerprint of ls using f's algorithm.
+func (f Fingerprinter) Fingerprint(ls labels.Labels) model.Fingerprint {
+	switch f.algo {
+	case XXHash:
+		return fingerprintXXHash(ls)
+	default:
+		return fingerprintFNV1a(ls)
+	}
+}
+
+var sepBytes = []byte{sepByte}
+
+func fingerprintXXHash(ls labels.Labels) model.Fingerprint {
+	h := xxhash.New()
+	for _, l := range ls {
+		_, _ = h.WriteString(l.Name)
+		_, _ = h.Write(sepBytes)
+		_, _ = h.WriteString(l.Value)
+		_, _ = h.Write(sepBytes)
+	}
+	return model.Fingerprint(h.Sum64())
+}
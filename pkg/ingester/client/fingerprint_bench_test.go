@@ -0,0 +1,64 @@
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+func benchmarkLabels() labels.Labels {
+	return labels.FromStrings(
+		"__name__", "cortex_ingester_benchmark_requests_total",
+		"job", "cortex/ingester",
+		"namespace", "cortex",
+		"cluster", "prod-us-east-1",
+		"instance", "cortex-ingester-7c9f8b6d4-abcde",
+		"status_code", "200",
+		"method", "GET",
+	)
+}
+
+func BenchmarkFingerprintFNV1a(b *testing.B) {
+	ls := benchmarkLabels()
+	fp := NewFingerprinter(FNV1a)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fp.Fingerprint(ls)
+	}
+}
+
+func BenchmarkFingerprintXXHash(b *testing.B) {
+	ls := benchmarkLabels()
+	fp := NewFingerprinter(XXHash)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fp.Fingerprint(ls)
+	}
+}
+
+func BenchmarkFingerprintFNV1aManyLabels(b *testing.B) {
+	builder := labels.NewBuilder(benchmarkLabels())
+	for i := 0; i < 20; i++ {
+		builder.Set(fmt.Sprintf("extra_label_%d", i), fmt.Sprintf("value_%d", i))
+	}
+	ls := builder.Labels()
+	fp := NewFingerprinter(FNV1a)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fp.Fingerprint(ls)
+	}
+}
+
+func BenchmarkFingerprintXXHashManyLabels(b *testing.B) {
+	builder := labels.NewBuilder(benchmarkLabels())
+	for i := 0; i < 20; i++ {
+		builder.Set(fmt.Sprintf("extra_label_%d", i), fmt.Sprintf("value_%d", i))
+	}
+	ls := builder.Labels()
+	fp := NewFingerprinter(XXHash)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = fp.Fingerprint(ls)
+	}
+}
@@ -0,0 +1,144 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// maxMappedFP is the largest fingerprint value that is part of the reserved
+// range used for mapped fingerprints. The FPMapper also has to deal with fast
+// fingerprints falling within this range "by chance".
+const maxMappedFP model.Fingerprint = 1 << 50
+
+const separatorString = string('\xff')
+
+var collisionsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Namespace: "cortex",
+	Name:      "fp_mapping_collisions_total",
+	Help:      "The number of fingerprint collisions detected, and new fingerprints mapped.",
+})
+
+func init() {
+	prometheus.MustRegister(collisionsTotal)
+}
+
+// FpToSeries is the part of the in-memory series index FPMapper needs in
+// order to tell whether a fast fingerprint already belongs to a known
+// series, so it can distinguish a real collision from a cache miss.
+type FpToSeries interface {
+	// GetMetricWithFP returns the label set stored under fp, and whether a
+	// series is stored under fp at all.
+	GetMetricWithFP(fp model.Fingerprint) (labels.Labels, bool)
+}
+
+// FPMapper is used to map fingerprints in order to work around fingerprint
+// collisions. If the fast (and collision-prone) fingerprint function
+// client.Fingerprint maps two different label sets to the same fingerprint,
+// one of the two label sets is assigned a new, mapped fingerprint, drawn from
+// the reserved range [0, maxMappedFP]. FPMapper is adapted from Prometheus'
+// fpMapper.
+type FPMapper struct {
+	// highestMappedFP has to be aligned for atomic operations.
+	highestMappedFP model.Fingerprint
+
+	mtx        sync.RWMutex
+	fpToSeries FpToSeries
+	// mappings maps original fingerprints to a map of unique label set
+	// strings to the fingerprint they have been mapped to.
+	mappings map[model.Fingerprint]map[string]model.Fingerprint
+}
+
+// NewFPMapper returns an FPMapper ready to use. The fpToSeries lookup is used
+// to detect the common case where the raw fingerprint already belongs to a
+// series held in memory, so no mapping is required.
+func NewFPMapper(fpToSeries FpToSeries) *FPMapper {
+	return &FPMapper{
+		fpToSeries: fpToSeries,
+		mappings:   map[model.Fingerprint]map[string]model.Fingerprint{},
+	}
+}
+
+// MapFP takes a raw fingerprint (as returned by client.Fingerprint) and the
+// metric it was calculated from, and returns a truly unique fingerprint. The
+// returned fingerprint is always equal to the input fingerprint if no
+// collision is involved. If a collision is detected, a mapped fingerprint
+// from the reserved range is returned instead, stably for the same metric.
+func (m *FPMapper) MapFP(fp model.Fingerprint, metric labels.Labels) model.Fingerprint {
+	// If the fp is in the reserved mapped range, it always has to be mapped,
+	// since real fast fingerprints falling into that range are collisions
+	// with reserved ones by definition.
+	if fp <= maxMappedFP {
+		return m.maybeAddMapping(fp, metric)
+	}
+
+	// Most common case: the fp already belongs to a series held in memory.
+	if existing, ok := m.fpToSeries.GetMetricWithFP(fp); ok {
+		if labels.Equal(existing, metric) {
+			return fp
+		}
+		return m.maybeAddMapping(fp, metric)
+	}
+
+	// The fp is not currently in memory. See if we have mapped it before.
+	m.mtx.RLock()
+	mappedFPs, fpAlreadyMapped := m.mappings[fp]
+	m.mtx.RUnlock()
+	if fpAlreadyMapped {
+		if mappedFP, ok := mappedFPs[metricToUniqueString(metric)]; ok {
+			return mappedFP
+		}
+	}
+	// Either fp was never mapped, or it was mapped for a different metric.
+	// Either way, the raw fingerprint is still available for this metric.
+	return fp
+}
+
+// maybeAddMapping is only ever called for detected collisions. It returns the
+// existing mapping if there is one, or allocates and records a new one.
+func (m *FPMapper) maybeAddMapping(fp model.Fingerprint, metric labels.Labels) model.Fingerprint {
+	ms := metricToUniqueString(metric)
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	mappedFPs, ok := m.mappings[fp]
+	if ok {
+		if mappedFP, ok := mappedFPs[ms]; ok {
+			return mappedFP
+		}
+	} else {
+		mappedFPs = map[string]model.Fingerprint{}
+		m.mappings[fp] = mappedFPs
+	}
+
+	mappedFP := m.nextMappedFP()
+	mappedFPs[ms] = mappedFP
+	collisionsTotal.Inc()
+	return mappedFP
+}
+
+func (m *FPMapper) nextMappedFP() model.Fingerprint {
+	mappedFP := model.Fingerprint(atomic.AddUint64((*uint64)(&m.highestMappedFP), 1))
+	if mappedFP > maxMappedFP {
+		panic(fmt.Errorf("more than %d fingerprints mapped in reserved range", maxMappedFP))
+	}
+	return mappedFP
+}
+
+// metricToUniqueString turns a (sorted) label set into a string in a
+// reproducible and collision-free way: the same label set always produces
+// the same string, and different label sets always produce different
+// strings.
+func metricToUniqueString(m labels.Labels) string {
+	parts := make([]string, 0, len(m))
+	for _, pair := range m {
+		parts = append(parts, pair.Name+separatorString+pair.Value)
+	}
+	return strings.Join(parts, separatorString)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// fakeFpToSeries is a minimal FpToSeries backed by a plain map, standing in
+// for the ingester's real in-memory series index.
+type fakeFpToSeries map[model.Fingerprint]labels.Labels
+
+func (f fakeFpToSeries) GetMetricWithFP(fp model.Fingerprint) (labels.Labels, bool) {
+	l, ok := f[fp]
+	return l, ok
+}
+
+// TestFPMapperCollisions reuses the pathological label-set pairs from
+// TestFingerprintCollisions to prove that, despite sharing a fast
+// fingerprint, the two series end up with distinct mapped fingerprints.
+func TestFPMapperCollisions(t *testing.T) {
+	c1 := labels.FromStrings("8yn0iYCKYHlIj4-BwPqk", "hello")
+	c2 := labels.FromStrings("GReLUrM4wMqfg9yzV3KQ", "hello")
+	verifyMappedFPsDistinct(t, c1, c2)
+
+	const _label1 = "ypfajYg2lsv"
+	const _label2 = "KiqbryhzUpn"
+
+	metric := labels.NewBuilder(labels.FromStrings("__name__", "logs"))
+	c1 = metric.Set("_", _label1).Labels()
+	c2 = metric.Set("_", _label2).Labels()
+	verifyMappedFPsDistinct(t, c1, c2)
+
+	metric = labels.NewBuilder(labels.FromStrings("__name__", "up", "instance", "hello"))
+	c1 = metric.Set("_", _label1).Labels()
+	c2 = metric.Set("_", _label2).Labels()
+	verifyMappedFPsDistinct(t, c1, c2)
+
+	const Alabel1 = "K6sjsNNczPl"
+	const Alabel2 = "cswpLMIZpwt"
+
+	metric = labels.NewBuilder(labels.FromStrings("__name__", "up", "Z", "hello"))
+	c1 = metric.Set("A", Alabel1).Labels()
+	c2 = metric.Set("A", Alabel2).Labels()
+	verifyMappedFPsDistinct(t, c1, c2)
+}
+
+func verifyMappedFPsDistinct(t *testing.T, ls1, ls2 labels.Labels) {
+	t.Helper()
+
+	fp1, fp2 := Fingerprint(ls1), Fingerprint(ls2)
+	if fp1 != fp2 {
+		t.Fatalf("expected %v and %v to collide on their raw fingerprint, got %016x and %016x", ls1, ls2, fp1, fp2)
+	}
+
+	// ls1 is already resident in memory; ls2 is the new series colliding with it.
+	mapper := NewFPMapper(fakeFpToSeries{fp1: ls1})
+
+	mapped1 := mapper.MapFP(fp1, ls1)
+	if mapped1 != fp1 {
+		t.Fatalf("expected resident series %v to map to its own fingerprint, got %016x", ls1, mapped1)
+	}
+
+	mapped2 := mapper.MapFP(fp2, ls2)
+	if mapped2 == fp1 {
+		t.Fatalf("expected colliding series %v to be mapped away from %016x, got the same fingerprint back", ls2, fp1)
+	}
+	if mapped2 > maxMappedFP {
+		t.Fatalf("expected mapped fingerprint %016x to fall in the reserved range [0, %016x]", mapped2, maxMappedFP)
+	}
+
+	if again := mapper.MapFP(fp2, ls2); again != mapped2 {
+		t.Fatalf("expected mapping for %v to be stable, got %016x then %016x", ls2, mapped2, again)
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"io"
+	"unsafe"
+
+	"github.com/prometheus/prometheus/pkg/exemplar"
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// ParseIntoLabelAdapters parses data (a text or OpenMetrics exposition format
+// scrape body, per contentType) and invokes cb once per sample, with mt set
+// to the most recently seen TYPE entry in data; the metric name on that TYPE
+// line is not compared with the sample's. It reuses a single
+// []LabelAdapter buffer across samples, casting it to *labels.Labels via
+// unsafe the same way FromLabelAdaptersToLabels does, so that unlike the
+// naive Parser -> labels.Labels -> FromLabelsToLabelAdapters chain, no
+// intermediate labels.Labels or per-sample label slice is ever allocated.
+//
+// OpenMetrics exemplar lines trailing a sample are drained (not surfaced to
+// cb) purely to keep the parser positioned correctly for the next entry.
+//
+// The buffer passed to cb is only valid for the duration of the call; cb
+// must copy it (e.g. via FromLabelAdaptersToLabelsWithCopy) if it needs to
+// retain it past that call.
+func ParseIntoLabelAdapters(data []byte, contentType string, cb func(ts int64, l []LabelAdapter, v float64, mt textparse.MetricType) error) error {
+	p := textparse.New(data, contentType)
+
+	var (
+		buf []LabelAdapter
+		ex  exemplar.Exemplar
+		mt  = textparse.MetricTypeUnknown
+	)
+
+	for {
+		entry, err := p.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		switch entry {
+		case textparse.EntryType:
+			_, mt = p.Type()
+		case textparse.EntryHelp, textparse.EntryUnit, textparse.EntryComment:
+			// No sample carried by these entries; nothing for cb to see.
+		case textparse.EntrySeries:
+			buf = buf[:0]
+			lset := (*labels.Labels)(unsafe.Pointer(&buf))
+			_, ts, v := p.Series()
+			p.Metric(lset)
+			buf = *(*[]LabelAdapter)(unsafe.Pointer(lset))
+
+			var timestamp int64
+			if ts != nil {
+				timestamp = *ts
+			}
+			if err := cb(timestamp, buf, v, mt); err != nil {
+				return err
+			}
+
+			// OpenMetrics trails a sample with zero or more exemplar lines;
+			// drain them so the parser lands on the next real entry. We
+			// don't surface exemplars through cb (its signature has no room
+			// for one), but we must still consume them off a reused
+			// exemplar.Exemplar to avoid allocating per exemplar.
+			for p.Exemplar(&ex) {
+				ex = exemplar.Exemplar{}
+			}
+		}
+	}
+}
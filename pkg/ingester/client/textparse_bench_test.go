@@ -0,0 +1,61 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+	"github.com/prometheus/prometheus/pkg/textparse"
+)
+
+// buildScrapeBody generates a synthetic Prometheus text-format scrape body
+// with numSeries distinct series, used to compare the zero-copy and naive
+// parsing paths under realistic cardinality.
+func buildScrapeBody(numSeries int) []byte {
+	var sb strings.Builder
+	for i := 0; i < numSeries; i++ {
+		fmt.Fprintf(&sb, "cortex_bench_requests_total{job=\"cortex\",instance=\"ingester-%d\",status_code=\"200\"} %d\n", i, i)
+	}
+	return []byte(sb.String())
+}
+
+func BenchmarkParseIntoLabelAdapters(b *testing.B) {
+	body := buildScrapeBody(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		err := ParseIntoLabelAdapters(body, "", func(ts int64, l []LabelAdapter, v float64, mt textparse.MetricType) error {
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkParseNaive parses the same body via Parser -> labels.Labels ->
+// FromLabelsToLabelAdapters, allocating a fresh labels.Labels per sample, to
+// quantify the allocations ParseIntoLabelAdapters avoids.
+func BenchmarkParseNaive(b *testing.B) {
+	body := buildScrapeBody(10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		p := textparse.New(body, "")
+		for {
+			entry, err := p.Next()
+			if err != nil {
+				break
+			}
+			if entry != textparse.EntrySeries {
+				continue
+			}
+			var lset labels.Labels
+			p.Metric(&lset)
+			_ = FromLabelsToLabelAdapters(lset)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/prometheus/prometheus/pkg/labels"
+)
+
+// ErrDuplicateLabelName is returned when a label set carries two or more
+// entries with the same label name.
+type ErrDuplicateLabelName struct {
+	Name     string
+	LabelSet string
+}
+
+func (e *ErrDuplicateLabelName) Error() string {
+	return fmt.Sprintf("duplicate label name %q in %s", e.Name, e.LabelSet)
+}
+
+// ValidateLabels checks ls, which must already be sorted by name, for two or
+// more entries sharing the same label name. FromLabelAdaptersToLabels and
+// FromLabelAdaptersToLabelsWithCopy keep their input's order and do not sort. Callers on the write
+// path, such as the distributor and ingester, should call this before
+// hashing or persisting a label set.
+//
+// This must not be applied to a query's matcher set: matchers legitimately
+// repeat a label name (e.g. {instance=~"web.*", instance!="web99"}).
+func ValidateLabels(ls labels.Labels) error {
+	for i := 1; i < len(ls); i++ {
+		if ls[i].Name == ls[i-1].Name {
+			return &ErrDuplicateLabelName{Name: ls[i].Name, LabelSet: ls.String()}
+		}
+	}
+	return nil
+}